@@ -0,0 +1,112 @@
+// Package cache provides an in-memory, bounded cache for parsed pipeline
+// manifests, so that repeated requests for the same repository, ref and
+// path don't need to re-fetch and re-parse the file on every call.
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Key identifies a cached manifest by the SCM host and repository, ref
+// and path it was read from, and whether that manifest's images have
+// been verified against an image policy. Host is included alongside Repo
+// because "owner/repo" alone is not unique across providers: a GitHub
+// and a GitLab repository can share the same owner/repo path. Verify is
+// included so that a verified and an unverified response for the same
+// manifest are never conflated: serving a cached unverified response to
+// a request that asked for verification would defeat fail-closed image
+// policy enforcement.
+type Key struct {
+	Host   string
+	Repo   string
+	Ref    string
+	Path   string
+	Verify bool
+}
+
+// LRU is a fixed-size, least-recently-used cache of arbitrary values keyed
+// by Key. It is safe for concurrent use.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[Key]*list.Element
+}
+
+type entry struct {
+	key   Key
+	value interface{}
+}
+
+// New creates an LRU cache that holds up to capacity entries, evicting the
+// least-recently-used entry once it is full.
+func New(capacity int) *LRU {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &LRU{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    map[Key]*list.Element{},
+	}
+}
+
+// Get returns the cached value for key, if present.
+func (c *LRU) Get(key Key) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*entry).value, true
+}
+
+// Set stores value under key, evicting the least-recently-used entry if
+// the cache is at capacity.
+func (c *LRU) Set(key Key, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*entry).value = value
+		return
+	}
+	el := c.ll.PushFront(&entry{key: key, value: value})
+	c.items[key] = el
+	if c.ll.Len() > c.capacity {
+		c.removeOldest()
+	}
+}
+
+// Invalidate removes every entry for the repository identified by host
+// and repo, regardless of ref or path, and returns the keys that were
+// removed.
+func (c *LRU) Invalidate(host, repo string) []Key {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var removed []Key
+	for key, el := range c.items {
+		if key.Host != host || key.Repo != repo {
+			continue
+		}
+		c.ll.Remove(el)
+		delete(c.items, key)
+		removed = append(removed, key)
+	}
+	return removed
+}
+
+func (c *LRU) removeOldest() {
+	el := c.ll.Back()
+	if el == nil {
+		return
+	}
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
@@ -0,0 +1,71 @@
+package cache
+
+import "testing"
+
+func TestLRUGetSet(t *testing.T) {
+	c := New(2)
+	key := Key{Repo: "my-org/my-repo", Ref: "main", Path: "pipelines.yaml"}
+
+	if _, ok := c.Get(key); ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+
+	c.Set(key, "parsed-manifest")
+	got, ok := c.Get(key)
+	if !ok {
+		t.Fatal("expected a hit after Set")
+	}
+	if got != "parsed-manifest" {
+		t.Fatalf("got %v, want %q", got, "parsed-manifest")
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New(2)
+	k1 := Key{Repo: "org/one", Ref: "main", Path: "pipelines.yaml"}
+	k2 := Key{Repo: "org/two", Ref: "main", Path: "pipelines.yaml"}
+	k3 := Key{Repo: "org/three", Ref: "main", Path: "pipelines.yaml"}
+
+	c.Set(k1, 1)
+	c.Set(k2, 2)
+	c.Set(k3, 3)
+
+	if _, ok := c.Get(k1); ok {
+		t.Fatal("expected k1 to have been evicted")
+	}
+	if _, ok := c.Get(k2); !ok {
+		t.Fatal("expected k2 to still be cached")
+	}
+	if _, ok := c.Get(k3); !ok {
+		t.Fatal("expected k3 to still be cached")
+	}
+}
+
+func TestLRUInvalidate(t *testing.T) {
+	c := New(10)
+	c.Set(Key{Host: "github.com", Repo: "org/repo", Ref: "main", Path: "pipelines.yaml"}, 1)
+	c.Set(Key{Host: "github.com", Repo: "org/repo", Ref: "v1", Path: "envs/prod/pipelines.yaml"}, 2)
+	c.Set(Key{Host: "github.com", Repo: "org/other", Ref: "main", Path: "pipelines.yaml"}, 3)
+
+	removed := c.Invalidate("github.com", "org/repo")
+	if len(removed) != 2 {
+		t.Fatalf("got %d removed keys, want 2", len(removed))
+	}
+	if _, ok := c.Get(Key{Host: "github.com", Repo: "org/other", Ref: "main", Path: "pipelines.yaml"}); !ok {
+		t.Fatal("expected unrelated repo's entry to survive invalidation")
+	}
+}
+
+func TestLRUInvalidateDoesNotCrossHosts(t *testing.T) {
+	c := New(10)
+	c.Set(Key{Host: "github.com", Repo: "org/repo", Ref: "main", Path: "pipelines.yaml"}, 1)
+	c.Set(Key{Host: "gitlab.com", Repo: "org/repo", Ref: "main", Path: "pipelines.yaml"}, 2)
+
+	removed := c.Invalidate("gitlab.com", "org/repo")
+	if len(removed) != 1 {
+		t.Fatalf("got %d removed keys, want 1", len(removed))
+	}
+	if _, ok := c.Get(Key{Host: "github.com", Repo: "org/repo", Ref: "main", Path: "pipelines.yaml"}); !ok {
+		t.Fatal("expected the same-named repo on a different host to survive invalidation")
+	}
+}
@@ -0,0 +1,62 @@
+// Package events provides a small fan-out hub for broadcasting pipeline
+// manifest updates to connected watch clients (e.g. the SSE endpoint in
+// pkg/httpapi).
+package events
+
+import "sync"
+
+// Update describes a change to a repository's pipeline manifest. Host is
+// included alongside Repo so that subscribers can distinguish
+// repositories that share an owner/repo path across different SCM
+// providers.
+type Update struct {
+	Host string
+	Repo string
+	Ref  string
+	Path string
+}
+
+// Hub fans out Updates to any number of subscribers.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Update]struct{}
+}
+
+// NewHub creates an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: map[chan Update]struct{}{}}
+}
+
+// Subscribe registers a new subscriber, returning a channel of Updates and
+// an unsubscribe function that must be called once the subscriber is done
+// listening.
+func (h *Hub) Subscribe() (<-chan Update, func()) {
+	ch := make(chan Update, 8)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish sends u to every current subscriber. A subscriber whose channel
+// is full has u dropped rather than blocking the publisher.
+func (h *Hub) Publish(u Update) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package events
+
+import "testing"
+
+func TestHubPublishSubscribe(t *testing.T) {
+	h := NewHub()
+	ch, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+
+	want := Update{Host: "github.com", Repo: "org/repo", Ref: "main", Path: "pipelines.yaml"}
+	h.Publish(want)
+
+	got := <-ch
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestHubUnsubscribeStopsDelivery(t *testing.T) {
+	h := NewHub()
+	ch, unsubscribe := h.Subscribe()
+	unsubscribe()
+
+	h.Publish(Update{Repo: "org/repo"})
+
+	if _, ok := <-ch; ok {
+		t.Fatal("expected the channel to be closed after unsubscribing")
+	}
+}
@@ -0,0 +1,71 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const bitbucketAPIURL = "https://api.bitbucket.org/2.0"
+
+// bitbucketClient talks to Bitbucket Cloud (bitbucket.org), authenticating
+// with an access token sent as HTTP Basic auth under the x-token-auth
+// username.
+type bitbucketClient struct {
+	token      string
+	httpClient *http.Client
+}
+
+func newBitbucketClient(token string) *bitbucketClient {
+	return &bitbucketClient{token: token, httpClient: http.DefaultClient}
+}
+
+func (b *bitbucketClient) FileContents(ctx context.Context, repo RepoRef, path, ref string) ([]byte, error) {
+	u := fmt.Sprintf("%s/repositories/%s/%s/src/%s/%s", bitbucketAPIURL, repo.Owner, repo.Repo, ref, path)
+	return b.get(ctx, u)
+}
+
+func (b *bitbucketClient) DefaultBranch(ctx context.Context, repo RepoRef) (string, error) {
+	u := fmt.Sprintf("%s/repositories/%s/%s", bitbucketAPIURL, repo.Owner, repo.Repo)
+	body, err := b.get(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	var repoResp struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	if err := json.Unmarshal(body, &repoResp); err != nil {
+		return "", fmt.Errorf("failed to decode Bitbucket repo response: %w", err)
+	}
+	return repoResp.MainBranch.Name, nil
+}
+
+func (b *bitbucketClient) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if b.token != "" {
+		req.SetBasicAuth("x-token-auth", b.token)
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bitbucket API %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", u, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Bitbucket API %s returned %d: %s", u, resp.StatusCode, body)
+	}
+	return body, nil
+}
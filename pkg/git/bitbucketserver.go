@@ -0,0 +1,69 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// bitbucketServerClient talks to a self-hosted Bitbucket Server/Data
+// Center instance, authenticating with a personal access token sent as a
+// bearer token.
+type bitbucketServerClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func newBitbucketServerClient(host, token string) *bitbucketServerClient {
+	return &bitbucketServerClient{baseURL: "https://" + host, token: token, httpClient: http.DefaultClient}
+}
+
+func (b *bitbucketServerClient) FileContents(ctx context.Context, repo RepoRef, path, ref string) ([]byte, error) {
+	u := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/raw/%s?at=%s", b.baseURL, repo.Owner, repo.Repo, path, ref)
+	return b.get(ctx, u)
+}
+
+func (b *bitbucketServerClient) DefaultBranch(ctx context.Context, repo RepoRef) (string, error) {
+	u := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/branches/default", b.baseURL, repo.Owner, repo.Repo)
+	body, err := b.get(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	var branchResp struct {
+		DisplayID string `json:"displayId"`
+	}
+	if err := json.Unmarshal(body, &branchResp); err != nil {
+		return "", fmt.Errorf("failed to decode Bitbucket Server branch response: %w", err)
+	}
+	return branchResp.DisplayID, nil
+}
+
+func (b *bitbucketServerClient) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bitbucket Server API %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", u, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Bitbucket Server API %s returned %d: %s", u, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,90 @@
+package git
+
+import "fmt"
+
+// ClientFactory creates an authenticated SCM client for a given
+// repository URL.
+type ClientFactory interface {
+	// Create returns an SCM implementation appropriate for the host in
+	// rawURL, authenticated with token.
+	Create(rawURL, token string) (SCM, error)
+}
+
+// factory is the default ClientFactory. It inspects the host portion of
+// the repository URL and dispatches to the provider-specific client,
+// treating the configured self-hosted hosts as instances of GitLab,
+// Bitbucket Server or Gitea respectively.
+type factory struct {
+	gitlabHosts    map[string]bool
+	bitbucketHosts map[string]bool
+	giteaHosts     map[string]bool
+}
+
+// FactoryOption configures a ClientFactory returned from NewClientFactory.
+type FactoryOption func(*factory)
+
+// WithGitLabHosts registers additional hostnames (e.g. a self-hosted
+// GitLab instance) that should be treated as GitLab.
+func WithGitLabHosts(hosts ...string) FactoryOption {
+	return func(f *factory) {
+		for _, h := range hosts {
+			f.gitlabHosts[h] = true
+		}
+	}
+}
+
+// WithBitbucketServerHosts registers hostnames that should be treated as
+// Bitbucket Server (as opposed to bitbucket.org).
+func WithBitbucketServerHosts(hosts ...string) FactoryOption {
+	return func(f *factory) {
+		for _, h := range hosts {
+			f.bitbucketHosts[h] = true
+		}
+	}
+}
+
+// WithGiteaHosts registers hostnames that should be treated as Gitea.
+func WithGiteaHosts(hosts ...string) FactoryOption {
+	return func(f *factory) {
+		for _, h := range hosts {
+			f.giteaHosts[h] = true
+		}
+	}
+}
+
+// NewClientFactory creates a ClientFactory capable of producing SCM
+// clients for GitHub, GitLab (hosted or self-hosted), Bitbucket Cloud,
+// Bitbucket Server and Gitea.
+func NewClientFactory(opts ...FactoryOption) ClientFactory {
+	f := &factory{
+		gitlabHosts:    map[string]bool{"gitlab.com": true},
+		bitbucketHosts: map[string]bool{},
+		giteaHosts:     map[string]bool{},
+	}
+	for _, o := range opts {
+		o(f)
+	}
+	return f
+}
+
+func (f *factory) Create(rawURL, token string) (SCM, error) {
+	ref, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case ref.Host == "github.com":
+		return newGitHubClient(token), nil
+	case f.gitlabHosts[ref.Host]:
+		return newGitLabClient(ref.Host, token), nil
+	case ref.Host == "bitbucket.org":
+		return newBitbucketClient(token), nil
+	case f.bitbucketHosts[ref.Host]:
+		return newBitbucketServerClient(ref.Host, token), nil
+	case f.giteaHosts[ref.Host]:
+		return newGiteaClient(ref.Host, token), nil
+	default:
+		return nil, fmt.Errorf("unsupported git host %q", ref.Host)
+	}
+}
@@ -0,0 +1,56 @@
+// Package git provides clients for fetching file contents from the
+// various Git hosting providers that gitops-backend can read pipeline
+// configuration from.
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"net/url"
+)
+
+// ErrNotFound is returned by SCM implementations when the requested ref
+// or file does not exist in the repository.
+var ErrNotFound = errors.New("not found")
+
+// RepoRef identifies a repository on a specific SCM host.
+type RepoRef struct {
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// String returns the "owner/repo" form of the ref, as used in most
+// provider APIs.
+func (r RepoRef) String() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// SCM is implemented by the provider-specific clients, and is used to
+// fetch file contents from a repository at a given ref.
+type SCM interface {
+	// FileContents fetches the contents of path in repo at ref.
+	FileContents(ctx context.Context, repo RepoRef, path, ref string) ([]byte, error)
+
+	// DefaultBranch returns the name of the repository's default branch,
+	// used when no ref is requested explicitly.
+	DefaultBranch(ctx context.Context, repo RepoRef) (string, error)
+}
+
+// ParseURL parses a repository URL into a RepoRef, e.g.
+// https://github.com/my-org/my-repo.git -> {github.com my-org my-repo}
+func ParseURL(s string) (RepoRef, error) {
+	parsed, err := url.Parse(s)
+	if err != nil {
+		return RepoRef{}, fmt.Errorf("failed to parse %#v: %w", s, err)
+	}
+	path := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return RepoRef{}, fmt.Errorf("failed to parse %#v: expected a URL with an /owner/repo path", s)
+	}
+	return RepoRef{Host: parsed.Host, Owner: parts[0], Repo: parts[1]}, nil
+}
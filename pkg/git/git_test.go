@@ -0,0 +1,68 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    RepoRef
+		wantErr bool
+	}{
+		{
+			url:  "https://github.com/my-org/my-repo.git",
+			want: RepoRef{Host: "github.com", Owner: "my-org", Repo: "my-repo"},
+		},
+		{
+			url:  "https://gitlab.example.com/my-group/my-repo",
+			want: RepoRef{Host: "gitlab.example.com", Owner: "my-group", Repo: "my-repo"},
+		},
+		{
+			url:     "https://github.com/my-org",
+			wantErr: true,
+		},
+		{
+			url:     "://not-a-url",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseURL(tt.url)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseURL(%q) expected an error, got none", tt.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseURL(%q) returned an unexpected error: %s", tt.url, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("ParseURL(%q) mismatch (-want +got):\n%s", tt.url, diff)
+		}
+	}
+}
+
+func TestFactoryCreateUnsupportedHost(t *testing.T) {
+	f := NewClientFactory()
+	_, err := f.Create("https://unknown.example.com/my-org/my-repo", "token")
+	if err == nil {
+		t.Fatal("expected an error for an unsupported host")
+	}
+}
+
+func TestFactoryCreateSelfHostedGitLab(t *testing.T) {
+	f := NewClientFactory(WithGitLabHosts("gitlab.example.com"))
+	scm, err := f.Create("https://gitlab.example.com/my-group/my-repo", "token")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := scm.(*gitLabClient); !ok {
+		t.Fatalf("expected a *gitLabClient, got %T", scm)
+	}
+}
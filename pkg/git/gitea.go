@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// giteaClient talks to a Gitea instance, authenticating with a personal
+// access token sent via the Authorization header.
+type giteaClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func newGiteaClient(host, token string) *giteaClient {
+	return &giteaClient{baseURL: "https://" + host, token: token, httpClient: http.DefaultClient}
+}
+
+func (g *giteaClient) FileContents(ctx context.Context, repo RepoRef, path, ref string) ([]byte, error) {
+	u := fmt.Sprintf("%s/api/v1/repos/%s/%s/contents/%s?ref=%s", g.baseURL, repo.Owner, repo.Repo, path, ref)
+	body, err := g.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	var content struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := json.Unmarshal(body, &content); err != nil {
+		return nil, fmt.Errorf("failed to decode Gitea response for %s: %w", path, err)
+	}
+	if content.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported content encoding %q for %s", content.Encoding, path)
+	}
+	return base64.StdEncoding.DecodeString(content.Content)
+}
+
+func (g *giteaClient) DefaultBranch(ctx context.Context, repo RepoRef) (string, error) {
+	u := fmt.Sprintf("%s/api/v1/repos/%s/%s", g.baseURL, repo.Owner, repo.Repo)
+	body, err := g.get(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	var repoResp struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.Unmarshal(body, &repoResp); err != nil {
+		return "", fmt.Errorf("failed to decode Gitea repo response: %w", err)
+	}
+	return repoResp.DefaultBranch, nil
+}
+
+func (g *giteaClient) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.token != "" {
+		req.Header.Set("Authorization", "token "+g.token)
+	}
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Gitea API %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", u, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Gitea API %s returned %d: %s", u, resp.StatusCode, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+// gitHubClient talks to github.com's REST API, authenticating with either
+// a personal access token or a GitHub App installation token, both of
+// which are sent as a bearer token.
+type gitHubClient struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+func newGitHubClient(token string) *gitHubClient {
+	return &gitHubClient{apiURL: githubAPIURL, token: token, httpClient: http.DefaultClient}
+}
+
+type githubContentResponse struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+func (g *gitHubClient) FileContents(ctx context.Context, repo RepoRef, path, ref string) ([]byte, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", g.apiURL, repo.Owner, repo.Repo, path, ref)
+	body, err := g.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	var content githubContentResponse
+	if err := json.Unmarshal(body, &content); err != nil {
+		return nil, fmt.Errorf("failed to decode GitHub response for %s: %w", path, err)
+	}
+	if content.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported content encoding %q for %s", content.Encoding, path)
+	}
+	return base64.StdEncoding.DecodeString(content.Content)
+}
+
+func (g *gitHubClient) DefaultBranch(ctx context.Context, repo RepoRef) (string, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s", g.apiURL, repo.Owner, repo.Repo)
+	body, err := g.get(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	var repoResp struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.Unmarshal(body, &repoResp); err != nil {
+		return "", fmt.Errorf("failed to decode GitHub repo response: %w", err)
+	}
+	return repoResp.DefaultBranch, nil
+}
+
+func (g *gitHubClient) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GitHub API %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", u, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API %s returned %d: %s", u, resp.StatusCode, body)
+	}
+	return body, nil
+}
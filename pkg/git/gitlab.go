@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// gitLabClient talks to a GitLab instance (gitlab.com or self-hosted),
+// authenticating with a personal or project access token sent via the
+// PRIVATE-TOKEN header.
+type gitLabClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func newGitLabClient(host, token string) *gitLabClient {
+	return &gitLabClient{baseURL: "https://" + host, token: token, httpClient: http.DefaultClient}
+}
+
+func (g *gitLabClient) FileContents(ctx context.Context, repo RepoRef, path, ref string) ([]byte, error) {
+	project := url.QueryEscape(repo.String())
+	u := fmt.Sprintf("%s/api/v4/projects/%s/repository/files/%s?ref=%s", g.baseURL, project, url.QueryEscape(path), url.QueryEscape(ref))
+	body, err := g.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := json.Unmarshal(body, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode GitLab response for %s: %w", path, err)
+	}
+	if file.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported content encoding %q for %s", file.Encoding, path)
+	}
+	return base64.StdEncoding.DecodeString(file.Content)
+}
+
+func (g *gitLabClient) DefaultBranch(ctx context.Context, repo RepoRef) (string, error) {
+	project := url.QueryEscape(repo.String())
+	u := fmt.Sprintf("%s/api/v4/projects/%s", g.baseURL, project)
+	body, err := g.get(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	var projResp struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.Unmarshal(body, &projResp); err != nil {
+		return "", fmt.Errorf("failed to decode GitLab project response: %w", err)
+	}
+	return projResp.DefaultBranch, nil
+}
+
+func (g *gitLabClient) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	}
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GitLab API %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", u, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitLab API %s returned %d: %s", u, resp.StatusCode, body)
+	}
+	return body, nil
+}
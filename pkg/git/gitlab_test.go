@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitLabClientFileContents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("PRIVATE-TOKEN"), "a-token"; got != want {
+			t.Errorf("got PRIVATE-TOKEN header %q, want %q", got, want)
+		}
+		w.Write([]byte(`{"content":"` + base64.StdEncoding.EncodeToString([]byte("apps: []")) + `","encoding":"base64"}`))
+	}))
+	defer srv.Close()
+
+	c := &gitLabClient{baseURL: srv.URL, token: "a-token", httpClient: srv.Client()}
+	got, err := c.FileContents(context.Background(), RepoRef{Owner: "my-group", Repo: "my-repo"}, "pipelines.yaml", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "apps: []" {
+		t.Errorf("got body %q, want %q", got, "apps: []")
+	}
+}
+
+func TestGitLabClientDefaultBranch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"default_branch":"main"}`))
+	}))
+	defer srv.Close()
+
+	c := &gitLabClient{baseURL: srv.URL, httpClient: srv.Client()}
+	got, err := c.DefaultBranch(context.Background(), RepoRef{Owner: "my-group", Repo: "my-repo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "main" {
+		t.Errorf("got default branch %q, want %q", got, "main")
+	}
+}
+
+func TestGitLabClientFileContentsReturnsErrNotFoundFor404(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := &gitLabClient{baseURL: srv.URL, httpClient: srv.Client()}
+	_, err := c.FileContents(context.Background(), RepoRef{Owner: "my-group", Repo: "my-repo"}, "missing.yaml", "main")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", err)
+	}
+}
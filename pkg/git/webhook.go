@@ -0,0 +1,65 @@
+package git
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Provider identifies the SCM provider that sent a webhook payload, so
+// that the correct signature scheme can be used to verify it.
+type Provider string
+
+const (
+	ProviderGitHub    Provider = "github"
+	ProviderGitLab    Provider = "gitlab"
+	ProviderBitbucket Provider = "bitbucket"
+)
+
+// ErrInvalidSignature is returned when a webhook payload fails signature
+// verification.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// VerifySignature checks a webhook payload's signature against secret,
+// using the verification scheme for provider:
+//
+//   - GitHub (and Bitbucket Server) send "X-Hub-Signature-256:
+//     sha256=<hex>" over the raw body, HMAC-SHA256 keyed with the webhook
+//     secret.
+//   - GitLab sends the configured secret verbatim in "X-Gitlab-Token",
+//     which is compared directly rather than HMAC'd.
+//   - Bitbucket Cloud signs with HMAC-SHA1 in "X-Hub-Signature".
+func VerifySignature(provider Provider, secret string, body []byte, header string) error {
+	switch provider {
+	case ProviderGitHub:
+		return verifyHMACSignature(sha256.New, "sha256=", secret, body, header)
+	case ProviderGitLab:
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(header)) != 1 {
+			return ErrInvalidSignature
+		}
+		return nil
+	case ProviderBitbucket:
+		return verifyHMACSignature(sha1.New, "sha1=", secret, body, header)
+	default:
+		return fmt.Errorf("unsupported webhook provider %q", provider)
+	}
+}
+
+func verifyHMACSignature(newHash func() hash.Hash, prefix, secret string, body []byte, header string) error {
+	want, err := hex.DecodeString(strings.TrimPrefix(header, prefix))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), want) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifySignatureGitHub(t *testing.T) {
+	secret := "s3cr3t"
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	header := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if err := VerifySignature(ProviderGitHub, secret, body, header); err != nil {
+		t.Fatalf("expected a valid signature, got: %s", err)
+	}
+	if err := VerifySignature(ProviderGitHub, secret, body, "sha256=deadbeef"); err == nil {
+		t.Fatal("expected an invalid signature to be rejected")
+	}
+}
+
+func TestVerifySignatureGitLab(t *testing.T) {
+	secret := "s3cr3t"
+	if err := VerifySignature(ProviderGitLab, secret, nil, secret); err != nil {
+		t.Fatalf("expected a valid token, got: %s", err)
+	}
+	if err := VerifySignature(ProviderGitLab, secret, nil, "wrong"); err == nil {
+		t.Fatal("expected a mismatched token to be rejected")
+	}
+}
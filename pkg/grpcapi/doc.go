@@ -0,0 +1,13 @@
+// Package grpcapi exposes the GitOpsBackend gRPC service defined in
+// proto/gitopsbackend.proto, built on the same pkg/pipelines.Service and
+// pkg/events.Hub used by pkg/httpapi, so that gRPC and HTTP clients see
+// the same data without gitops-backend having two copies of the
+// fetch/auth/cache logic.
+//
+// This package depends on the generated gitopsbackendpb package, which
+// is not checked in; run `make generate` (or `go generate ./...`) with
+// protoc, protoc-gen-go and protoc-gen-go-grpc on $PATH before building
+// or testing it. `make build`/`make test` run generate automatically.
+package grpcapi
+
+//go:generate protoc --go_out=../.. --go_opt=module=github.com/rhd-gitops-examples/gitops-backend --go-grpc_out=../.. --go-grpc_opt=module=github.com/rhd-gitops-examples/gitops-backend -I ../../proto ../../proto/gitopsbackend.proto
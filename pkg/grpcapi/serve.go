@@ -0,0 +1,26 @@
+package grpcapi
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+)
+
+// Serve runs httpSrv and grpcSrv on the same listener lis, distinguishing
+// them by content-type via cmux so that gitops-backend's REST and gRPC
+// APIs can be reached on a single address. It blocks until one of the
+// servers returns, and returns that error.
+func Serve(lis net.Listener, httpSrv *http.Server, grpcSrv *grpc.Server) error {
+	m := cmux.New(lis)
+	grpcLis := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	httpLis := m.Match(cmux.Any())
+
+	errs := make(chan error, 3)
+	go func() { errs <- grpcSrv.Serve(grpcLis) }()
+	go func() { errs <- httpSrv.Serve(httpLis) }()
+	go func() { errs <- m.Serve() }()
+
+	return <-errs
+}
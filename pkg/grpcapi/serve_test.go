@@ -0,0 +1,42 @@
+package grpcapi
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestServeRoutesHTTPRequestsToTheHTTPServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpSrv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})}
+	grpcSrv := grpc.NewServer()
+	defer grpcSrv.Stop()
+
+	errs := make(chan error, 1)
+	go func() { errs <- Serve(lis, httpSrv, grpcSrv) }()
+
+	resp, err := http.Get("http://" + lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusTeapot {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusTeapot)
+	}
+
+	grpcSrv.Stop()
+	select {
+	case <-errs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for Serve to return")
+	}
+}
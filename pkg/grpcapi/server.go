@@ -0,0 +1,119 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/events"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/grpcapi/gitopsbackendpb"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/pipelines"
+)
+
+// authMetadataKey is the gRPC metadata key clients set their auth token
+// under, the equivalent of the HTTP API's bearer token.
+const authMetadataKey = "authorization"
+
+// Server implements gitopsbackendpb.GitOpsBackendServer on top of the
+// same pipelines.Service and events.Hub pkg/httpapi uses, so both APIs
+// share one fetch/auth/cache/notify implementation.
+type Server struct {
+	gitopsbackendpb.UnimplementedGitOpsBackendServer
+	service *pipelines.Service
+	hub     *events.Hub
+}
+
+// NewServer creates a Server that resolves and fetches manifests with
+// service, and streams WatchPipelines updates from hub.
+func NewServer(service *pipelines.Service, hub *events.Hub) *Server {
+	return &Server{service: service, hub: hub}
+}
+
+// GetPipelines fetches the repository's pipelines manifest, the gRPC
+// equivalent of GET /pipelines.
+func (s *Server) GetPipelines(ctx context.Context, req *gitopsbackendpb.GetPipelinesRequest) (*gitopsbackendpb.PipelinesResponse, error) {
+	manifest, err := s.service.Fetch(ctx, pipelines.Request{
+		URL:       req.GetUrl(),
+		Token:     tokenFromMetadata(ctx),
+		Ref:       req.GetRef(),
+		Path:      req.GetPath(),
+		SecretRef: secretRefFromRequest(req),
+	})
+	if err != nil {
+		if errors.Is(err, git.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &gitopsbackendpb.PipelinesResponse{
+		Repo:     manifest.Repo.String(),
+		Ref:      manifest.Ref,
+		Path:     manifest.Path,
+		Manifest: manifest.Body,
+	}, nil
+}
+
+// WatchPipelines streams a PipelinesResponse each time the requested
+// repository's manifest is invalidated, the gRPC equivalent of
+// GET /pipelines/watch.
+func (s *Server) WatchPipelines(req *gitopsbackendpb.WatchPipelinesRequest, stream gitopsbackendpb.GitOpsBackend_WatchPipelinesServer) error {
+	repo, err := git.ParseURL(req.GetUrl())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	updates, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case u, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if u.Host != repo.Host || u.Repo != repo.String() {
+				continue
+			}
+			if err := stream.Send(&gitopsbackendpb.PipelinesResponse{Repo: u.Repo, Ref: u.Ref, Path: u.Path}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// secretRefFromRequest returns the Secret identified by req's
+// secret_namespace/secret_name fields, or pipelines.DefaultSecretRef if
+// neither is set, matching the fallback pkg/httpapi's GetPipelines
+// applies for the equivalent HTTP request.
+func secretRefFromRequest(req *gitopsbackendpb.GetPipelinesRequest) types.NamespacedName {
+	if req.GetSecretNamespace() == "" && req.GetSecretName() == "" {
+		return pipelines.DefaultSecretRef
+	}
+	return types.NamespacedName{
+		Namespace: req.GetSecretNamespace(),
+		Name:      req.GetSecretName(),
+	}
+}
+
+// tokenFromMetadata returns the value of the "authorization" gRPC
+// metadata key from ctx's incoming metadata, or "" if unset.
+func tokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(authMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
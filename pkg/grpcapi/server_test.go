@@ -0,0 +1,179 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/events"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/grpcapi/gitopsbackendpb"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/pipelines"
+)
+
+type stubSCM struct {
+	body []byte
+	err  error
+}
+
+func (s stubSCM) FileContents(ctx context.Context, repo git.RepoRef, path, ref string) ([]byte, error) {
+	return s.body, s.err
+}
+
+func (s stubSCM) DefaultBranch(ctx context.Context, repo git.RepoRef) (string, error) {
+	return "main", nil
+}
+
+type stubClientFactory struct {
+	scm git.SCM
+}
+
+func (f stubClientFactory) Create(rawURL, token string) (git.SCM, error) {
+	return f.scm, nil
+}
+
+type stubSecretGetter struct {
+	gotSecretRef types.NamespacedName
+}
+
+func (s *stubSecretGetter) SecretToken(ctx context.Context, host, token string, secretRef types.NamespacedName) (string, error) {
+	s.gotSecretRef = secretRef
+	return "a-token", nil
+}
+
+func newTestServer(scm git.SCM) (*Server, *stubSecretGetter, *events.Hub) {
+	hub := events.NewHub()
+	secretGetter := &stubSecretGetter{}
+	svc := pipelines.NewService(stubClientFactory{scm: scm}, secretGetter)
+	return NewServer(svc, hub), secretGetter, hub
+}
+
+func TestServerGetPipelinesReturnsManifest(t *testing.T) {
+	s, _, _ := newTestServer(stubSCM{body: []byte("environments: []")})
+
+	resp, err := s.GetPipelines(context.Background(), &gitopsbackendpb.GetPipelinesRequest{
+		Url:  "https://github.com/my-org/my-repo.git",
+		Ref:  "v1.2.3",
+		Path: "pipelines.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Ref != "v1.2.3" {
+		t.Errorf("got ref %q, want %q", resp.Ref, "v1.2.3")
+	}
+	if string(resp.Manifest) != "environments: []" {
+		t.Errorf("got manifest %q, want the fetched body", resp.Manifest)
+	}
+}
+
+func TestServerGetPipelinesReturnsNotFoundStatus(t *testing.T) {
+	s, _, _ := newTestServer(stubSCM{err: git.ErrNotFound})
+
+	_, err := s.GetPipelines(context.Background(), &gitopsbackendpb.GetPipelinesRequest{
+		Url: "https://github.com/my-org/my-repo.git",
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("got status %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
+func TestServerGetPipelinesReturnsInvalidArgumentForOtherErrors(t *testing.T) {
+	s, _, _ := newTestServer(stubSCM{})
+
+	_, err := s.GetPipelines(context.Background(), &gitopsbackendpb.GetPipelinesRequest{
+		Url: "://not-a-url",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("got status %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+func TestServerGetPipelinesDefaultsSecretRefWhenUnset(t *testing.T) {
+	s, secretGetter, _ := newTestServer(stubSCM{body: []byte("environments: []")})
+
+	if _, err := s.GetPipelines(context.Background(), &gitopsbackendpb.GetPipelinesRequest{
+		Url: "https://github.com/my-org/my-repo.git",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if secretGetter.gotSecretRef != pipelines.DefaultSecretRef {
+		t.Errorf("got secret ref %+v, want the default %+v", secretGetter.gotSecretRef, pipelines.DefaultSecretRef)
+	}
+}
+
+func TestServerGetPipelinesHonoursExplicitSecretRef(t *testing.T) {
+	s, secretGetter, _ := newTestServer(stubSCM{body: []byte("environments: []")})
+
+	want := types.NamespacedName{Namespace: "my-ns", Name: "my-secret"}
+	if _, err := s.GetPipelines(context.Background(), &gitopsbackendpb.GetPipelinesRequest{
+		Url:             "https://github.com/my-org/my-repo.git",
+		SecretNamespace: want.Namespace,
+		SecretName:      want.Name,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if secretGetter.gotSecretRef != want {
+		t.Errorf("got secret ref %+v, want %+v", secretGetter.gotSecretRef, want)
+	}
+}
+
+// fakeWatchStream is a minimal gitopsbackendpb.GitOpsBackend_WatchPipelinesServer
+// that delivers sent responses over a channel instead of writing them to a
+// real connection.
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	received chan *gitopsbackendpb.PipelinesResponse
+}
+
+func (f *fakeWatchStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchStream) Send(r *gitopsbackendpb.PipelinesResponse) error {
+	f.received <- r
+	return nil
+}
+
+// TestServerWatchPipelinesFiltersToRequestedRepo asserts that an update for
+// a same-named repo on a different host is not mistaken for the requested
+// one. Subscription happens asynchronously inside WatchPipelines, so both
+// updates are republished on a short interval until the matching one is
+// delivered or the test times out.
+func TestServerWatchPipelinesFiltersToRequestedRepo(t *testing.T) {
+	s, _, hub := newTestServer(stubSCM{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeWatchStream{ctx: ctx, received: make(chan *gitopsbackendpb.PipelinesResponse, 4)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WatchPipelines(&gitopsbackendpb.WatchPipelinesRequest{Url: "https://github.com/my-org/my-repo.git"}, stream)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		hub.Publish(events.Update{Host: "gitlab.com", Repo: "my-org/my-repo", Ref: "main", Path: "pipelines.yaml"})
+		hub.Publish(events.Update{Host: "github.com", Repo: "my-org/my-repo", Ref: "main", Path: "pipelines.yaml"})
+
+		select {
+		case got := <-stream.received:
+			if got.Repo != "my-org/my-repo" {
+				t.Fatalf("got repo %q, want %q", got.Repo, "my-org/my-repo")
+			}
+			cancel()
+			<-done
+			return
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("timed out waiting for the matching update")
+		}
+	}
+}
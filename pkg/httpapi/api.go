@@ -3,107 +3,239 @@ package httpapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/julienschmidt/httprouter"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/yaml"
 
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/cache"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/events"
 	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
 	"github.com/rhd-gitops-examples/gitops-backend/pkg/httpapi/secrets"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/imagepolicy"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/logging"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/pipelines"
 )
 
 // DefaultSecretRef is the name looked up if none is provided in the URL.
-var DefaultSecretRef = types.NamespacedName{
-	Name:      "pipelines-app-gitops",
+var DefaultSecretRef = pipelines.DefaultSecretRef
+
+// DefaultWebhookSecretRef is the secret looked up for verifying incoming
+// webhook payloads.
+var DefaultWebhookSecretRef = types.NamespacedName{
+	Name:      "pipelines-app-gitops-webhook",
 	Namespace: "pipelines-app-delivery",
 }
 
+// manifestCacheCapacity is the number of parsed manifests the cache will
+// hold before evicting the least-recently-used entry.
+const manifestCacheCapacity = 256
+
 // APIRouter is an HTTP API for accessing app configurations.
 type APIRouter struct {
 	*httprouter.Router
-	gitClientFactory git.ClientFactory
 	secretGetter     secrets.SecretGetter
 	secretRef        types.NamespacedName
+	webhookSecretRef types.NamespacedName
+	manifestCache    *cache.LRU
+	hub              *events.Hub
+	imageVerifier    imagepolicy.Verifier
+	imageCache       *imagepolicy.Cache
+	logger           logr.Logger
+	pipelinesService *pipelines.Service
+}
+
+// RouterOption configures an APIRouter returned from NewRouter.
+type RouterOption func(*APIRouter)
+
+// WithImageVerifier enables image signature/provenance verification for
+// requests to /pipelines with ?verify=true, using v to check images
+// against a trust policy.
+func WithImageVerifier(v imagepolicy.Verifier) RouterOption {
+	return func(a *APIRouter) {
+		a.imageVerifier = v
+	}
+}
+
+// WithLogger overrides the base logr.Logger that requests are logged
+// against; the default is a production zap logger.
+func WithLogger(l logr.Logger) RouterOption {
+	return func(a *APIRouter) {
+		a.logger = l
+	}
 }
 
 // GePipelines fetches and returns the pipeline body.
 func (a *APIRouter) GetPipelines(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	logger := logging.FromContext(r.Context())
+
 	urlToFetch := r.URL.Query().Get("url")
 	if urlToFetch == "" {
-		log.Println("ERROR: could not get url from request")
+		logger.Info("could not get url from request")
 		http.Error(w, "missing parameter 'url'", http.StatusBadRequest)
 		return
 	}
+	logger = logger.WithValues("url", urlToFetch)
 
-	// TODO: replace this with logr or sugar.
-	log.Printf("urlToFetch = %#v\n", urlToFetch)
-	repo, err := parseURL(urlToFetch)
+	secretRef, ok := secretRefFromQuery(r.URL.Query())
+	if !ok {
+		secretRef = a.secretRef
+	}
+	resolved, err := a.pipelinesService.Resolve(r.Context(), pipelines.Request{
+		URL:       urlToFetch,
+		Token:     bearerToken(r),
+		Ref:       r.URL.Query().Get("ref"),
+		Path:      r.URL.Query().Get("path"),
+		SecretRef: secretRef,
+	})
 	if err != nil {
-		log.Printf("ERROR: failed to parse the URL: %s", err)
+		logger.Error(err, "failed to resolve the repository")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	logger = logger.WithValues("repo", resolved.Repo.String(), "ref", resolved.Ref, "secret", secretRef.String())
 
-	client, err := a.getAuthenticatedGitClient(r.Context(), r, urlToFetch)
-	if err != nil {
-		log.Println("ERROR: failed to get an authenticated client")
-		http.Error(w, "unable to authenticate request", http.StatusBadRequest)
+	verify := r.URL.Query().Get("verify") == "true"
+	cacheKey := cache.Key{Host: resolved.Repo.Host, Repo: resolved.Repo.String(), Ref: resolved.Ref, Path: resolved.Path, Verify: verify}
+	if cached, ok := a.manifestCache.Get(cacheKey); ok {
+		logger.Info("served pipelines from cache", "duration", time.Since(start))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cached)
 		return
 	}
 
-	// TODO: don't send back the error directly.
-	//
-	// Add a "not found" error that can be returned, otherwise it's a
-	// StatusInternalServerError.
-	log.Println("got an authenticated client")
-	body, err := client.FileContents(r.Context(), repo, "pipelines.yaml", "master")
+	manifest, err := a.pipelinesService.FetchAt(r.Context(), resolved)
 	if err != nil {
-		log.Printf("ERROR: failed to get file contents for repo %#v: %s", repo, err)
+		if errors.Is(err, git.ErrNotFound) {
+			logger.Error(err, "path not found at ref", "path", resolved.Path)
+			http.Error(w, fmt.Sprintf("%s not found at ref %s", resolved.Path, resolved.Ref), http.StatusNotFound)
+			return
+		}
+		logger.Error(err, "failed to get file contents", "path", resolved.Path)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	pipelines := &config{}
-	err = yaml.Unmarshal(body, &pipelines)
+	pipelinesConfig := &config{}
+	err = yaml.Unmarshal(manifest.Body, &pipelinesConfig)
 	if err != nil {
-		log.Printf("ERROR: failed to unmarshal body %s", err)
+		logger.Error(err, "failed to unmarshal pipelines.yaml")
 		http.Error(w, fmt.Sprintf("failed to unmarshal pipelines.yaml: %s", err.Error()), http.StatusBadRequest)
 		return
 	}
+	response := pipelinesToAppsResponse(pipelinesConfig)
+
+	if verify {
+		if err := a.verifyImages(r.Context(), response); err != nil {
+			var policyErr imagepolicy.PolicyError
+			if errors.As(err, &policyErr) {
+				logger.Error(err, "image policy rejected one or more images")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(policyErr)
+				return
+			}
+			logger.Error(err, "failed to verify images")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	a.manifestCache.Set(cacheKey, response)
+
+	logger.Info("served pipelines", "duration", time.Since(start))
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(pipelinesToAppsResponse(pipelines))
+	json.NewEncoder(w).Encode(response)
 }
 
-func (a *APIRouter) getAuthenticatedGitClient(ctx context.Context, req *http.Request, fetchURL string) (git.SCM, error) {
-	token := AuthToken(ctx)
-	secret, ok := secretRefFromQuery(req.URL.Query())
-	if !ok {
-		secret = a.secretRef
+// verifyImages checks the images referenced by each service in response
+// against the configured image policy, annotating responseService.Verified,
+// responseService.Signers and responseService.Provenance with the result.
+// It returns an imagepolicy.PolicyError listing every image that failed
+// verification, so that GetPipelines can fail closed.
+func (a *APIRouter) verifyImages(ctx context.Context, response []responseService) error {
+	if a.imageVerifier == nil {
+		return fmt.Errorf("image verification was requested but no image verifier is configured")
 	}
-	token, err := a.secretGetter.SecretToken(ctx, token, secret)
-	if err != nil {
-		return nil, err
+
+	var rejected imagepolicy.PolicyError
+	for i := range response {
+		results, err := imagepolicy.VerifyAll(ctx, a.imageVerifier, a.imageCache, response[i].Images)
+		var policyErr imagepolicy.PolicyError
+		if err != nil {
+			if !errors.As(err, &policyErr) {
+				return err
+			}
+			rejected = append(rejected, policyErr...)
+		}
+
+		verified := len(results) > 0
+		signers := map[string]bool{}
+		for _, result := range results {
+			verified = verified && result.Verified
+			for _, signer := range result.Signers {
+				signers[signer] = true
+			}
+			if result.Provenance != nil {
+				response[i].Provenance = result.Provenance
+			}
+		}
+		response[i].Verified = verified
+		for signer := range signers {
+			response[i].Signers = append(response[i].Signers, signer)
+		}
+	}
+
+	if len(rejected) > 0 {
+		return rejected
 	}
-	return a.gitClientFactory.Create(fetchURL, token)
+	return nil
 }
 
 // NewRouter creates and returns a new APIRouter.
-func NewRouter(c git.ClientFactory, s secrets.SecretGetter) *APIRouter {
-	api := &APIRouter{Router: httprouter.New(), gitClientFactory: c, secretGetter: s, secretRef: DefaultSecretRef}
-	api.HandlerFunc(http.MethodGet, "/pipelines", api.GetPipelines)
+func NewRouter(c git.ClientFactory, s secrets.SecretGetter, opts ...RouterOption) *APIRouter {
+	logger, err := logging.New()
+	if err != nil {
+		logger = logr.Discard()
+	}
+	api := &APIRouter{
+		Router:           httprouter.New(),
+		secretGetter:     s,
+		secretRef:        DefaultSecretRef,
+		webhookSecretRef: DefaultWebhookSecretRef,
+		manifestCache:    cache.New(manifestCacheCapacity),
+		hub:              events.NewHub(),
+		imageCache:       imagepolicy.NewCache(),
+		logger:           logger,
+		pipelinesService: pipelines.NewService(c, s),
+	}
+	for _, o := range opts {
+		o(api)
+	}
+	api.HandlerFunc(http.MethodGet, "/pipelines", api.withLogging(api.GetPipelines))
+	api.HandlerFunc(http.MethodGet, "/pipelines/watch", api.withLogging(api.WatchPipelines))
+	api.HandlerFunc(http.MethodPost, "/webhook", api.withLogging(api.Webhook))
 	return api
 }
 
-func parseURL(s string) (string, error) {
-	parsed, err := url.Parse(s)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse %#v: %w", s, err)
-	}
-	return strings.TrimLeft(strings.Trim(parsed.Path, ".git"), "/"), nil
+// withLogging wraps next so that requests to it carry a logger with a
+// request-scoped correlation ID, retrievable via logging.FromContext.
+func (a *APIRouter) withLogging(next http.HandlerFunc) http.HandlerFunc {
+	return logging.Middleware(a.logger, next).ServeHTTP
+}
+
+// bearerToken returns the token from r's "Authorization: Bearer <token>"
+// header, or "" if it isn't set, in which case the caller falls back to
+// looking one up via its secret ref.
+func bearerToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
 }
 
 func secretRefFromQuery(v url.Values) (types.NamespacedName, bool) {
@@ -0,0 +1,112 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/pipelines"
+)
+
+type recordingSCM struct {
+	defaultBranch string
+	body          []byte
+	err           error
+
+	gotRef, gotPath string
+}
+
+func (s *recordingSCM) FileContents(ctx context.Context, repo git.RepoRef, path, ref string) ([]byte, error) {
+	s.gotPath = path
+	s.gotRef = ref
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.body, nil
+}
+
+func (s *recordingSCM) DefaultBranch(ctx context.Context, repo git.RepoRef) (string, error) {
+	return s.defaultBranch, nil
+}
+
+type stubClientFactory struct {
+	scm git.SCM
+}
+
+func (f stubClientFactory) Create(rawURL, token string) (git.SCM, error) {
+	return f.scm, nil
+}
+
+type stubSecretGetter struct {
+	token string
+}
+
+func (s stubSecretGetter) SecretToken(ctx context.Context, host, token string, secretRef types.NamespacedName) (string, error) {
+	return s.token, nil
+}
+
+func TestGetPipelinesRequiresURL(t *testing.T) {
+	router := NewRouter(stubClientFactory{scm: &recordingSCM{}}, stubSecretGetter{token: "a-token"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pipelines", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetPipelinesReturnsNotFoundForMissingRefOrPath(t *testing.T) {
+	scm := &recordingSCM{err: git.ErrNotFound}
+	router := NewRouter(stubClientFactory{scm: scm}, stubSecretGetter{token: "a-token"})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pipelines?url=https://github.com/my-org/my-repo.git&ref=missing-ref", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetPipelinesDefaultsToRepositoryBranchAndManifestPath(t *testing.T) {
+	scm := &recordingSCM{defaultBranch: "main", body: []byte("environments: []")}
+	router := NewRouter(stubClientFactory{scm: scm}, stubSecretGetter{token: "a-token"})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pipelines?url=https://github.com/my-org/my-repo.git", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if scm.gotRef != "main" {
+		t.Errorf("got ref %q, want the discovered default branch", scm.gotRef)
+	}
+	if scm.gotPath != pipelines.DefaultManifestPath {
+		t.Errorf("got path %q, want %q", scm.gotPath, pipelines.DefaultManifestPath)
+	}
+}
+
+func TestGetPipelinesHonoursExplicitRefAndPath(t *testing.T) {
+	scm := &recordingSCM{body: []byte("environments: []")}
+	router := NewRouter(stubClientFactory{scm: scm}, stubSecretGetter{token: "a-token"})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pipelines?url=https://github.com/my-org/my-repo.git&ref=v1.2.3&path=envs/prod/pipelines.yaml", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if scm.gotRef != "v1.2.3" {
+		t.Errorf("got ref %q, want %q", scm.gotRef, "v1.2.3")
+	}
+	if scm.gotPath != "envs/prod/pipelines.yaml" {
+		t.Errorf("got path %q, want %q", scm.gotPath, "envs/prod/pipelines.yaml")
+	}
+}
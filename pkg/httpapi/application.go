@@ -0,0 +1,146 @@
+package httpapi
+
+import (
+	"sort"
+
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/imagepolicy"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/parser"
+)
+
+// nameLabel is the label used to associate a cluster resource with the
+// pipelines.yaml service it belongs to.
+const nameLabel = "app.kubernetes.io/name"
+
+// config is the decoded shape of a pipelines.yaml manifest.
+type config struct {
+	Environments []*environment `json:"environments"`
+}
+
+// environment is a deployment target holding a set of applications.
+type environment struct {
+	Name    string         `json:"name"`
+	Cluster string         `json:"cluster"`
+	Apps    []*application `json:"apps"`
+}
+
+// application groups the services that make up a single app.
+type application struct {
+	Name     string    `json:"name"`
+	Services []service `json:"services"`
+}
+
+// service is a single deployable component of an application, sourced
+// from its own Git repository.
+type service struct {
+	Name      string `json:"name"`
+	SourceURL string `json:"source_url"`
+}
+
+// source identifies where a service's code is hosted, and the provider
+// that hosts it.
+type source struct {
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+// responseService is the shape of a single service returned by GET
+// /pipelines, optionally annotated with the cluster resources that
+// belong to it and the result of image policy verification.
+type responseService struct {
+	Name       string                  `json:"name"`
+	Source     source                  `json:"source"`
+	Images     []string                `json:"images,omitempty"`
+	Resources  []*parser.Resource      `json:"resources,omitempty"`
+	Verified   bool                    `json:"verified,omitempty"`
+	Signers    []string                `json:"signers,omitempty"`
+	Provenance *imagepolicy.Provenance `json:"provenance,omitempty"`
+}
+
+// parseServicesFromResources matches res against the services declared
+// in env by the app.kubernetes.io/name label, returning a responseService
+// for each service that has at least one matching resource. Services
+// with no matching resources are omitted.
+func parseServicesFromResources(env *environment, res []*parser.Resource) ([]responseService, error) {
+	services := []responseService{}
+	for _, app := range env.Apps {
+		for _, svc := range app.Services {
+			matched := resourcesNamed(svc.Name, res)
+			if len(matched) == 0 {
+				continue
+			}
+			services = append(services, responseService{
+				Name:      svc.Name,
+				Source:    sourceFromURL(svc.SourceURL),
+				Images:    imagesOf(matched),
+				Resources: matched,
+			})
+		}
+	}
+	return services, nil
+}
+
+// pipelinesToAppsResponse flattens a parsed pipelines.yaml config into the
+// per-service shape returned by GET /pipelines. Images and Resources are
+// left unset here, since populating them requires scanning a service's
+// live cluster resources via parseServicesFromResources.
+func pipelinesToAppsResponse(c *config) []responseService {
+	services := []responseService{}
+	for _, env := range c.Environments {
+		for _, app := range env.Apps {
+			for _, svc := range app.Services {
+				services = append(services, responseService{
+					Name:   svc.Name,
+					Source: sourceFromURL(svc.SourceURL),
+				})
+			}
+		}
+	}
+	return services
+}
+
+// resourcesNamed returns the resources in res labelled with name under
+// nameLabel, in their original order.
+func resourcesNamed(name string, res []*parser.Resource) []*parser.Resource {
+	var matched []*parser.Resource
+	for _, r := range res {
+		if r.Labels[nameLabel] == name {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// imagesOf returns the sorted, de-duplicated set of images referenced by
+// res.
+func imagesOf(res []*parser.Resource) []string {
+	seen := map[string]bool{}
+	var images []string
+	for _, r := range res {
+		for _, img := range r.Images {
+			if seen[img] {
+				continue
+			}
+			seen[img] = true
+			images = append(images, img)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
+
+// sourceFromURL returns the source for sourceURL, with Type set to the
+// host that sourceURL resolves to (e.g. "github.com", "gitlab.com") so
+// that responseService.Source.Type always reflects the service's actual
+// provider rather than assuming GitHub. An empty or unparseable
+// sourceURL is reported with no Type.
+func sourceFromURL(sourceURL string) source {
+	if sourceURL == "" {
+		return source{}
+	}
+	repo, err := git.ParseURL(sourceURL)
+	if err != nil {
+		return source{URL: sourceURL}
+	}
+	return source{URL: sourceURL, Type: repo.Host}
+}
@@ -0,0 +1,16 @@
+// Package secrets resolves authentication tokens for talking to the
+// various Git providers from Kubernetes Secrets.
+package secrets
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SecretGetter resolves the token to use when authenticating to host,
+// preferring a token passed in explicitly over a value looked up from
+// secretRef.
+type SecretGetter interface {
+	SecretToken(ctx context.Context, host, token string, secretRef types.NamespacedName) (string, error)
+}
@@ -0,0 +1,157 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/events"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/logging"
+)
+
+// webhookPayload captures the handful of fields common to GitHub, GitLab
+// and Bitbucket push/pull-request webhook payloads that are needed to
+// identify which repository changed.
+type webhookPayload struct {
+	Repository struct {
+		CloneURL string `json:"clone_url"`
+		HTMLURL  string `json:"html_url"`
+		WebURL   string `json:"web_url"` // GitLab
+	} `json:"repository"`
+}
+
+func (p webhookPayload) repoURL() string {
+	for _, u := range []string{p.Repository.CloneURL, p.Repository.HTMLURL, p.Repository.WebURL} {
+		if u != "" {
+			return u
+		}
+	}
+	return ""
+}
+
+// webhookSignature inspects the request headers to determine which
+// provider sent the webhook, and returns the header value to verify it
+// against.
+func webhookSignature(r *http.Request) (git.Provider, string) {
+	if sig := r.Header.Get("X-Hub-Signature-256"); sig != "" {
+		return git.ProviderGitHub, sig
+	}
+	if tok := r.Header.Get("X-Gitlab-Token"); tok != "" {
+		return git.ProviderGitLab, tok
+	}
+	return git.ProviderBitbucket, r.Header.Get("X-Hub-Signature")
+}
+
+// Webhook handles push and pull-request/merge-request notifications from
+// GitHub, GitLab and Bitbucket, invalidating any cached manifests for the
+// affected repository and notifying connected /pipelines/watch clients.
+func (a *APIRouter) Webhook(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Error(err, "failed to read webhook body")
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	provider, sig := webhookSignature(r)
+	secret, err := a.secretGetter.SecretToken(r.Context(), "", "", a.webhookSecretRef)
+	if err != nil {
+		logger.Error(err, "failed to look up webhook secret", "secret", a.webhookSecretRef.String())
+		http.Error(w, "unable to verify webhook", http.StatusBadRequest)
+		return
+	}
+	if err := git.VerifySignature(provider, secret, body, sig); err != nil {
+		logger.Error(err, "webhook signature verification failed")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		logger.Error(err, "failed to decode webhook payload")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	repo, err := git.ParseURL(payload.repoURL())
+	if err != nil {
+		logger.Error(err, "failed to parse repository URL from webhook payload")
+		http.Error(w, "unrecognised repository", http.StatusBadRequest)
+		return
+	}
+	logger = logger.WithValues("repo", repo.String())
+
+	for _, key := range a.manifestCache.Invalidate(repo.Host, repo.String()) {
+		a.hub.Publish(events.Update{Host: key.Host, Repo: key.Repo, Ref: key.Ref, Path: key.Path})
+	}
+	logger.Info("invalidated manifest cache for repo")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WatchPipelines streams pipeline manifest updates for the repository
+// identified by the "url" query parameter over Server-Sent Events, as
+// they are invalidated by incoming webhooks.
+func (a *APIRouter) WatchPipelines(w http.ResponseWriter, r *http.Request) {
+	urlToFetch := r.URL.Query().Get("url")
+	if urlToFetch == "" {
+		http.Error(w, "missing parameter 'url'", http.StatusBadRequest)
+		return
+	}
+	repo, err := git.ParseURL(urlToFetch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	updates, unsubscribe := a.hub.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+			if u.Host != repo.Host || u.Repo != repo.String() {
+				continue
+			}
+			if err := json.NewEncoder(sseWriter{w}).Encode(u); err != nil {
+				logging.FromContext(r.Context()).Error(err, "failed to write SSE event")
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// sseWriter prefixes each write with the "data: " field required by the
+// Server-Sent Events framing, and terminates it with a blank line.
+type sseWriter struct {
+	w http.ResponseWriter
+}
+
+func (s sseWriter) Write(p []byte) (int, error) {
+	if _, err := s.w.Write([]byte("data: ")); err != nil {
+		return 0, err
+	}
+	if _, err := s.w.Write(p); err != nil {
+		return 0, err
+	}
+	_, err := s.w.Write([]byte("\n"))
+	return len(p), err
+}
@@ -0,0 +1,35 @@
+package imagepolicy
+
+import "sync"
+
+// Cache stores verification Results keyed by image digest, so that
+// repeated requests for the same image don't re-run verification on every
+// call. Unlike pkg/cache.LRU it is unbounded; deployments are expected to
+// key on content-addressed digests, which are cheap to keep forever
+// relative to the cost of re-verifying them.
+type Cache struct {
+	mu    sync.RWMutex
+	items map[string]Result
+}
+
+// NewCache creates an empty Cache.
+func NewCache() *Cache {
+	return &Cache{items: map[string]Result{}}
+}
+
+// Get returns the cached Result for digest, if present.
+func (c *Cache) Get(digest string) (Result, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r, ok := c.items[digest]
+	return r, ok
+}
+
+// Set stores result under digest.
+func (c *Cache) Set(digest string, result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[digest] = result
+}
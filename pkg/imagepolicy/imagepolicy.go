@@ -0,0 +1,60 @@
+// Package imagepolicy verifies the signature and provenance of container
+// images referenced by a parsed pipeline manifest against a configured
+// trust policy, so that responses can be annotated with whether an image
+// is signed by a trusted identity and, where available, a summary of its
+// SLSA provenance.
+package imagepolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Provenance summarises the SLSA attestation associated with an image, if
+// one was found.
+type Provenance struct {
+	Subject   string
+	Predicate string
+	Builder   string
+}
+
+// Result is the outcome of verifying a single image reference against the
+// trust policy.
+type Result struct {
+	Image      string
+	Verified   bool
+	Signers    []string
+	Provenance *Provenance
+}
+
+// Verifier resolves an image reference against a trust policy.
+// TrustRootVerifier is the implementation in this package; VerifyAll
+// accepts any Verifier, so other implementations can be used in its place.
+type Verifier interface {
+	// Verify checks imageRef against the trust policy, returning whether
+	// it is signed by a trusted identity and any provenance found for it.
+	Verify(ctx context.Context, imageRef string) (Result, error)
+}
+
+// RejectedError explains why a single image failed verification.
+type RejectedError struct {
+	Image  string
+	Reason string
+}
+
+func (e RejectedError) Error() string {
+	return fmt.Sprintf("image %q rejected: %s", e.Image, e.Reason)
+}
+
+// PolicyError aggregates the images that failed verification, returned
+// when verification is requested but one or more images fail closed.
+type PolicyError []RejectedError
+
+func (e PolicyError) Error() string {
+	reasons := make([]string, len(e))
+	for i, r := range e {
+		reasons[i] = r.Error()
+	}
+	return fmt.Sprintf("image policy rejected %d image(s): %s", len(e), strings.Join(reasons, "; "))
+}
@@ -0,0 +1,100 @@
+package imagepolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTrustRootVerifierMatchesLongestPrefix(t *testing.T) {
+	v := NewTrustRootVerifier(TrustPolicy{
+		Signers: map[string][]TrustedSigner{
+			"ghcr.io/my-org/":        {{Identity: "ci@my-org.example", Issuer: "https://token.actions.githubusercontent.com"}},
+			"ghcr.io/my-org/locked/": {{Identity: "release@my-org.example", Issuer: "https://token.actions.githubusercontent.com"}},
+		},
+	}, NoTransparencyLog{})
+
+	got, err := v.Verify(context.Background(), "ghcr.io/my-org/locked/app:v1@sha256:abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Verified {
+		t.Fatal("expected image under a trusted prefix to verify")
+	}
+	if len(got.Signers) != 1 || got.Signers[0] != "release@my-org.example" {
+		t.Fatalf("got signers %v, want the longest-prefix match", got.Signers)
+	}
+}
+
+func TestTrustRootVerifierRejectsUntrustedRepo(t *testing.T) {
+	v := NewTrustRootVerifier(TrustPolicy{
+		Signers: map[string][]TrustedSigner{
+			"ghcr.io/my-org/": {{Identity: "ci@my-org.example"}},
+		},
+	}, NoTransparencyLog{})
+
+	got, err := v.Verify(context.Background(), "docker.io/library/busybox:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Verified {
+		t.Fatal("expected an image outside the trust policy to be unverified")
+	}
+}
+
+func TestTrustRootVerifierRequiresTransparencyLogEntry(t *testing.T) {
+	v := NewTrustRootVerifier(TrustPolicy{
+		Signers: map[string][]TrustedSigner{
+			"ghcr.io/my-org/": {{Identity: "ci@my-org.example"}},
+		},
+		RequireTransparencyLog: true,
+	}, stubLog{hasEntry: false})
+
+	got, err := v.Verify(context.Background(), "ghcr.io/my-org/app:v1@sha256:abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Verified {
+		t.Fatal("expected verification to fail without a corroborating transparency-log entry")
+	}
+}
+
+func TestVerifyAllUsesCacheAndReportsRejections(t *testing.T) {
+	v := NewTrustRootVerifier(TrustPolicy{
+		Signers: map[string][]TrustedSigner{
+			"ghcr.io/my-org/": {{Identity: "ci@my-org.example"}},
+		},
+	}, NoTransparencyLog{})
+	cache := NewCache()
+	cache.Set("sha256:cached", Result{Image: "cached", Verified: true, Signers: []string{"cached-signer"}})
+
+	images := []string{
+		"ghcr.io/my-org/app:v1@sha256:cached",
+		"ghcr.io/my-org/app:v2@sha256:def",
+		"docker.io/library/busybox:latest",
+	}
+
+	results, err := VerifyAll(context.Background(), v, cache, images)
+
+	var polErr PolicyError
+	if !errors.As(err, &polErr) {
+		t.Fatalf("got err %v, want a PolicyError", err)
+	}
+	if len(polErr) != 1 || polErr[0].Image != "docker.io/library/busybox:latest" {
+		t.Fatalf("got rejected %v, want only the untrusted image", polErr)
+	}
+	if !results[0].Verified || results[0].Signers[0] != "cached-signer" {
+		t.Fatalf("got %+v, want the cached result reused", results[0])
+	}
+	if !results[1].Verified {
+		t.Fatal("expected the second image to verify against the trust policy")
+	}
+}
+
+type stubLog struct {
+	hasEntry bool
+}
+
+func (s stubLog) HasEntry(ctx context.Context, digest string) (bool, error) {
+	return s.hasEntry, nil
+}
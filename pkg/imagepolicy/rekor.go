@@ -0,0 +1,21 @@
+package imagepolicy
+
+import "context"
+
+// TransparencyLog looks up signing events for an image digest in a Rekor
+// (or Rekor-compatible) transparency log, used by Verifier implementations
+// to corroborate a signature before trusting it.
+type TransparencyLog interface {
+	// HasEntry reports whether digest has at least one signing entry
+	// recorded in the transparency log.
+	HasEntry(ctx context.Context, digest string) (bool, error)
+}
+
+// NoTransparencyLog is a TransparencyLog that never corroborates an entry,
+// for deployments that trust signatures without a transparency log.
+type NoTransparencyLog struct{}
+
+// HasEntry always reports false.
+func (NoTransparencyLog) HasEntry(ctx context.Context, digest string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,94 @@
+package imagepolicy
+
+import (
+	"context"
+	"strings"
+)
+
+// TrustedSigner identifies an identity permitted to sign images under a
+// TrustPolicy entry, in the sigstore "keyless" sense of an OIDC identity
+// plus the issuer that vouches for it.
+type TrustedSigner struct {
+	Identity string
+	Issuer   string
+}
+
+// TrustPolicy maps image repositories (matched by longest prefix, e.g.
+// "ghcr.io/my-org/") to the signers trusted to sign images published
+// there.
+type TrustPolicy struct {
+	Signers map[string][]TrustedSigner
+
+	// RequireTransparencyLog, when set, additionally requires a
+	// corroborating entry in the TransparencyLog before an image is
+	// considered verified.
+	RequireTransparencyLog bool
+}
+
+// TrustRootVerifier is a Verifier backed by a static TrustPolicy, the kind
+// of trust root used by Notary v2 and Cosign key-based verification, or a
+// sigstore keyless identity policy. Real deployments populate Policy from
+// their Notary/Cosign/sigstore configuration; this type only implements
+// the matching and transparency-log corroboration, so it can stand in for
+// whichever of those a deployment chooses.
+type TrustRootVerifier struct {
+	Policy TrustPolicy
+	Log    TransparencyLog
+}
+
+// NewTrustRootVerifier creates a TrustRootVerifier for policy, corroborating
+// matches against log. Pass NoTransparencyLog{} if policy.RequireTransparencyLog
+// is false and no log lookup is needed.
+func NewTrustRootVerifier(policy TrustPolicy, log TransparencyLog) *TrustRootVerifier {
+	return &TrustRootVerifier{Policy: policy, Log: log}
+}
+
+// Verify resolves imageRef's repository against the trust policy, and, if
+// RequireTransparencyLog is set, confirms the digest appears in the
+// transparency log before reporting it as verified.
+func (v *TrustRootVerifier) Verify(ctx context.Context, imageRef string) (Result, error) {
+	signers, ok := v.matchSigners(imageRef)
+	if !ok {
+		return Result{Image: imageRef}, nil
+	}
+
+	if v.Policy.RequireTransparencyLog {
+		found, err := v.Log.HasEntry(ctx, digestOf(imageRef))
+		if err != nil {
+			return Result{}, err
+		}
+		if !found {
+			return Result{Image: imageRef}, nil
+		}
+	}
+
+	identities := make([]string, len(signers))
+	for i, s := range signers {
+		identities[i] = s.Identity
+	}
+	return Result{Image: imageRef, Verified: true, Signers: identities}, nil
+}
+
+// matchSigners finds the longest repository prefix in the policy that
+// imageRef starts with.
+func (v *TrustRootVerifier) matchSigners(imageRef string) ([]TrustedSigner, bool) {
+	var best string
+	for prefix := range v.Policy.Signers {
+		if strings.HasPrefix(imageRef, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return nil, false
+	}
+	return v.Policy.Signers[best], true
+}
+
+// digestOf returns the digest portion of an image reference pinned by
+// digest (repo@sha256:...), or the reference itself if it isn't.
+func digestOf(imageRef string) string {
+	if i := strings.LastIndex(imageRef, "@"); i != -1 {
+		return imageRef[i+1:]
+	}
+	return imageRef
+}
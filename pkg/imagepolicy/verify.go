@@ -0,0 +1,38 @@
+package imagepolicy
+
+import "context"
+
+// VerifyAll resolves each of images against verifier, short-circuiting
+// through cache by digest. It returns one Result per image, in the same
+// order they were given, and a PolicyError listing every image that
+// failed verification so that callers can fail closed.
+func VerifyAll(ctx context.Context, verifier Verifier, cache *Cache, images []string) ([]Result, error) {
+	results := make([]Result, len(images))
+	var rejected PolicyError
+
+	for i, image := range images {
+		digest := digestOf(image)
+		if cached, ok := cache.Get(digest); ok {
+			results[i] = cached
+			if !cached.Verified {
+				rejected = append(rejected, RejectedError{Image: image, Reason: "not signed by a trusted identity"})
+			}
+			continue
+		}
+
+		result, err := verifier.Verify(ctx, image)
+		if err != nil {
+			return nil, err
+		}
+		cache.Set(digest, result)
+		results[i] = result
+		if !result.Verified {
+			rejected = append(rejected, RejectedError{Image: image, Reason: "not signed by a trusted identity"})
+		}
+	}
+
+	if len(rejected) > 0 {
+		return results, rejected
+	}
+	return results, nil
+}
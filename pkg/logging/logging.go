@@ -0,0 +1,43 @@
+// Package logging provides structured, request-scoped logging for
+// gitops-backend, built on go-logr/logr with a zap backend. A logger
+// carrying the current request's correlation ID is attached to each
+// request's context by Middleware, and retrieved with FromContext.
+package logging
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+var loggerKey = contextKey{}
+
+// New builds the logr.Logger used by the application, backed by a
+// production zap configuration.
+func New() (logr.Logger, error) {
+	zl, err := zap.NewProduction()
+	if err != nil {
+		return logr.Logger{}, err
+	}
+	return zapr.NewLogger(zl), nil
+}
+
+// NewContext returns a copy of ctx carrying logger, retrievable with
+// FromContext.
+func NewContext(ctx context.Context, logger logr.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the logger attached to ctx by NewContext/Middleware,
+// or logr.Discard() if none was attached.
+func FromContext(ctx context.Context) logr.Logger {
+	logger, ok := ctx.Value(loggerKey).(logr.Logger)
+	if !ok {
+		return logr.Discard()
+	}
+	return logger
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestFromContextReturnsDiscardLoggerByDefault(t *testing.T) {
+	if logger := FromContext(context.Background()); logger.GetSink() != logr.Discard().GetSink() {
+		t.Fatalf("got a non-discard logger with no logger attached to the context")
+	}
+}
+
+func TestFromContextReturnsAttachedLogger(t *testing.T) {
+	want := logr.Discard().WithName("test")
+	ctx := NewContext(context.Background(), want)
+
+	if got := FromContext(ctx); got.GetSink() != want.GetSink() {
+		t.Fatal("expected the attached logger to be returned")
+	}
+}
+
+func TestMiddlewareGeneratesRequestIDWhenMissing(t *testing.T) {
+	var gotID string
+	h := Middleware(logr.Discard(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = r.Header.Get(RequestIDHeader)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/pipelines", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if gotID != "" {
+		t.Fatal("did not expect the inbound request to be mutated with a generated ID")
+	}
+	if rec.Header().Get(RequestIDHeader) == "" {
+		t.Fatal("expected a generated request ID to be echoed back on the response")
+	}
+}
+
+func TestMiddlewarePropagatesExistingRequestID(t *testing.T) {
+	h := Middleware(logr.Discard(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/pipelines", nil)
+	req.Header.Set(RequestIDHeader, "a-known-id")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "a-known-id" {
+		t.Fatalf("got request ID %q, want the inbound value echoed back", got)
+	}
+}
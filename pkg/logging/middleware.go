@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/go-logr/logr"
+)
+
+// RequestIDHeader is the header checked for an existing correlation ID
+// before one is generated.
+const RequestIDHeader = "X-Request-ID"
+
+// Middleware attaches a request-scoped logger to each request's context,
+// carrying a correlation ID taken from the RequestIDHeader or generated if
+// absent. It also echoes the correlation ID back on RequestIDHeader so
+// callers can correlate their own logs with the server's.
+func Middleware(base logr.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = generateRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		logger := base.WithValues("requestID", requestID)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), logger)))
+	})
+}
+
+// generateRequestID returns a random 16-byte hex-encoded correlation ID.
+func generateRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,16 @@
+// Package parser holds the decoded shape of a Kubernetes manifest that
+// gitops-backend matches against the services declared in pipelines.yaml.
+package parser
+
+// Resource is a single Kubernetes resource decoded from a manifest,
+// reduced to the fields needed to associate it with a pipelines.yaml
+// service: its kind/name identity, labels, and any container images it
+// references.
+type Resource struct {
+	Group   string
+	Version string
+	Kind    string
+	Name    string
+	Labels  map[string]string
+	Images  []string
+}
@@ -0,0 +1,132 @@
+// Package pipelines holds the logic for resolving and fetching a
+// pipelines manifest from a Git repository that is shared by
+// gitops-backend's HTTP and gRPC APIs: parsing the repository URL,
+// authenticating against the right provider, resolving a ref and path,
+// and fetching the manifest's raw bytes.
+package pipelines
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/httpapi/secrets"
+)
+
+// DefaultManifestPath is the path looked up in the repository if none is
+// given in a Request.
+const DefaultManifestPath = "pipelines.yaml"
+
+// DefaultSecretRef is the Secret resolved for a Request whose SecretRef
+// is unset, shared by both pkg/httpapi and pkg/grpcapi so that a caller
+// that omits a secret ref gets the same default token lookup on either
+// API.
+var DefaultSecretRef = types.NamespacedName{
+	Name:      "pipelines-app-gitops",
+	Namespace: "pipelines-app-delivery",
+}
+
+// Service resolves and fetches pipelines manifests from Git repositories.
+type Service struct {
+	gitClientFactory git.ClientFactory
+	secretGetter     secrets.SecretGetter
+}
+
+// NewService creates a Service that authenticates with s and creates Git
+// clients with c.
+func NewService(c git.ClientFactory, s secrets.SecretGetter) *Service {
+	return &Service{gitClientFactory: c, secretGetter: s}
+}
+
+// Request describes a manifest to fetch.
+type Request struct {
+	// URL is the repository URL to fetch from, e.g.
+	// https://github.com/my-org/my-repo.git.
+	URL string
+	// Token, if set, is used instead of looking one up via SecretRef.
+	Token string
+	// Ref is the branch, tag or commit SHA to fetch at. If empty, the
+	// repository's default branch is discovered and used.
+	Ref string
+	// Path is the manifest's path within the repository. If empty,
+	// DefaultManifestPath is used.
+	Path string
+	// SecretRef is the Kubernetes Secret to resolve a token from when
+	// Token is empty.
+	SecretRef types.NamespacedName
+}
+
+// Manifest is a fetched, unparsed pipelines manifest.
+type Manifest struct {
+	Repo git.RepoRef
+	Ref  string
+	Path string
+	Body []byte
+}
+
+// ResolvedRef is a Request with its ref and path fully resolved (defaulted
+// and/or discovered from the repository) and an authenticated client
+// ready to fetch from it. Callers that want to cache on (repo, ref, path)
+// without always paying for the file fetch use Resolve then FetchAt;
+// callers that don't need that use Fetch directly.
+type ResolvedRef struct {
+	Client git.SCM
+	Repo   git.RepoRef
+	Ref    string
+	Path   string
+}
+
+// Resolve authenticates against req's repository and resolves its ref
+// (discovering the default branch if req.Ref is empty) and path (using
+// DefaultManifestPath if req.Path is empty), without fetching the
+// manifest itself.
+func (s *Service) Resolve(ctx context.Context, req Request) (ResolvedRef, error) {
+	repo, err := git.ParseURL(req.URL)
+	if err != nil {
+		return ResolvedRef{}, fmt.Errorf("failed to parse %#v: %w", req.URL, err)
+	}
+
+	token, err := s.secretGetter.SecretToken(ctx, repo.Host, req.Token, req.SecretRef)
+	if err != nil {
+		return ResolvedRef{}, err
+	}
+	client, err := s.gitClientFactory.Create(req.URL, token)
+	if err != nil {
+		return ResolvedRef{}, err
+	}
+
+	ref := req.Ref
+	if ref == "" {
+		ref, err = client.DefaultBranch(ctx, repo)
+		if err != nil {
+			return ResolvedRef{}, err
+		}
+	}
+	path := req.Path
+	if path == "" {
+		path = DefaultManifestPath
+	}
+
+	return ResolvedRef{Client: client, Repo: repo, Ref: ref, Path: path}, nil
+}
+
+// FetchAt fetches the manifest at an already-Resolve'd ref.
+func (s *Service) FetchAt(ctx context.Context, resolved ResolvedRef) (Manifest, error) {
+	body, err := resolved.Client.FileContents(ctx, resolved.Repo, resolved.Path, resolved.Ref)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return Manifest{Repo: resolved.Repo, Ref: resolved.Ref, Path: resolved.Path, Body: body}, nil
+}
+
+// Fetch resolves req against the appropriate Git provider and returns the
+// manifest's raw bytes.
+func (s *Service) Fetch(ctx context.Context, req Request) (Manifest, error) {
+	resolved, err := s.Resolve(ctx, req)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return s.FetchAt(ctx, resolved)
+}
@@ -0,0 +1,89 @@
+package pipelines
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rhd-gitops-examples/gitops-backend/pkg/git"
+)
+
+type stubSecretGetter struct {
+	token string
+	err   error
+}
+
+func (s stubSecretGetter) SecretToken(ctx context.Context, host, token string, secretRef types.NamespacedName) (string, error) {
+	if token != "" {
+		return token, nil
+	}
+	return s.token, s.err
+}
+
+type stubSCM struct {
+	defaultBranch string
+	body          []byte
+	err           error
+}
+
+func (s stubSCM) FileContents(ctx context.Context, repo git.RepoRef, path, ref string) ([]byte, error) {
+	return s.body, s.err
+}
+
+func (s stubSCM) DefaultBranch(ctx context.Context, repo git.RepoRef) (string, error) {
+	return s.defaultBranch, nil
+}
+
+type stubClientFactory struct {
+	scm git.SCM
+}
+
+func (f stubClientFactory) Create(rawURL, token string) (git.SCM, error) {
+	return f.scm, nil
+}
+
+func TestFetchUsesDefaultBranchAndPathWhenUnset(t *testing.T) {
+	svc := NewService(stubClientFactory{scm: stubSCM{defaultBranch: "main", body: []byte("apps: []")}}, stubSecretGetter{token: "a-token"})
+
+	got, err := svc.Fetch(context.Background(), Request{URL: "https://github.com/my-org/my-repo.git"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Ref != "main" {
+		t.Errorf("got ref %q, want the discovered default branch", got.Ref)
+	}
+	if got.Path != DefaultManifestPath {
+		t.Errorf("got path %q, want %q", got.Path, DefaultManifestPath)
+	}
+	if string(got.Body) != "apps: []" {
+		t.Errorf("got body %q, want the fetched manifest", got.Body)
+	}
+}
+
+func TestFetchHonoursExplicitRefAndPath(t *testing.T) {
+	svc := NewService(stubClientFactory{scm: stubSCM{body: []byte("apps: []")}}, stubSecretGetter{token: "a-token"})
+
+	got, err := svc.Fetch(context.Background(), Request{
+		URL:  "https://github.com/my-org/my-repo.git",
+		Ref:  "v1.2.3",
+		Path: "envs/prod/pipelines.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Ref != "v1.2.3" {
+		t.Errorf("got ref %q, want the requested ref", got.Ref)
+	}
+	if got.Path != "envs/prod/pipelines.yaml" {
+		t.Errorf("got path %q, want the requested path", got.Path)
+	}
+}
+
+func TestFetchReturnsErrorForInvalidURL(t *testing.T) {
+	svc := NewService(stubClientFactory{scm: stubSCM{}}, stubSecretGetter{})
+
+	if _, err := svc.Fetch(context.Background(), Request{URL: "://not-a-url"}); err == nil {
+		t.Fatal("expected an error for an invalid URL")
+	}
+}